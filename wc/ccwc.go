@@ -2,19 +2,36 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"runtime"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Counts holds the counting results for a given input.
 type Counts struct {
-	lines int
-	words int
-	bytes int
-	chars int
+	lines      int
+	words      int
+	bytes      int
+	chars      int
+	maxLineLen int
+	runes      int // explicit rune count, reported by -r
+	broken     int // syntactically-invalid UTF-8 sequences, reported by -b
+
+	// Extended byte stats, reported by -x/--extended.
+	lfLines         int // lines terminated by "\n" alone
+	crlfLines       int // lines terminated by "\r\n"
+	trailingWSLines int // lines with trailing whitespace before the terminator
+	nulBytes        int // 0x00 bytes
+	ffBytes         int // 0xFF bytes
+	highBytes       int // bytes >= 0x80
 }
 
 func main() {
@@ -24,14 +41,20 @@ func main() {
 	wordsFlag := flag.Bool("w", false, "count words")
 	bytesFlag := flag.Bool("c", false, "count bytes")
 	charsFlag := flag.Bool("m", false, "count characters")
-	longestLine := flag.Bool("L", false, "length of line containing most byte")	
+	longestLine := flag.Bool("L", false, "length of line containing most byte")
+	filesFrom := flag.String("files0-from", "", "read input from the files specified by NUL-terminated names in file F; if F is -, read names from standard input")
+	jobs := flag.Int("j", runtime.NumCPU(), "number of files to count concurrently")
+	extended := flag.Bool("x", false, "report extended byte stats (LF/CRLF lines, trailing whitespace, NUL/0xFF/high bytes) as TSV")
+	flag.BoolVar(extended, "extended", false, "alias for -x")
+	runesFlag := flag.Bool("r", false, "count runes")
+	brokenFlag := flag.Bool("b", false, "count syntactically-invalid UTF-8 sequences")
 
 	// Parse the flags from the command line.
 	flag.Parse()
 
 	// If no flags are specified, the default behavior is to count lines, words, and bytes.
 	// We check if all flags are false (their default value).
-	noFlags := !*linesFlag && !*wordsFlag && !*bytesFlag && !*charsFlag && !*longestLine
+	noFlags := !*linesFlag && !*wordsFlag && !*bytesFlag && !*charsFlag && !*longestLine && !*runesFlag && !*brokenFlag
 	if noFlags {
 		*linesFlag = true
 		*wordsFlag = true
@@ -41,97 +64,415 @@ func main() {
 	// Get the list of files from the command-line arguments.
 	files := flag.Args()
 
-	// If no files are provided, read from standard input.
-	if len(files) == 0 {
-		counts, err := count(os.Stdin)
+	// --files0-from reads the list of files to process from a NUL-separated
+	// list instead of the command line, mirroring GNU wc. It cannot be
+	// combined with positional file arguments.
+	if *filesFrom != "" {
+		if len(files) > 0 {
+			fmt.Fprintln(os.Stderr, "ccwc: file operands cannot be combined with --files0-from")
+			os.Exit(1)
+		}
+		names, err := readFiles0From(*filesFrom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ccwc: %v\n", err)
+			os.Exit(1)
+		}
+		files = names
+	}
+
+	// needChars turns on the -m chars counter and needWords turns on
+	// unicode.IsSpace word boundaries for non-ASCII runes; bytesOnly lets it
+	// skip scanning a regular file entirely when -c is the only thing asked for.
+	needChars := *charsFlag
+	needWords := *wordsFlag
+	bytesOnly := *bytesFlag && !*linesFlag && !*wordsFlag && !*charsFlag && !*longestLine && !*extended && !*runesFlag && !*brokenFlag
+
+	// If no files are provided and --files0-from was not given, read from
+	// standard input. An empty --files0-from list counts nothing.
+	if len(files) == 0 && *filesFrom == "" {
+		counts, err := count(os.Stdin, needChars, needWords)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error reading from stdin: %v\n", err)
 			os.Exit(1)
 		}
-		printCounts(counts, "", *linesFlag, *wordsFlag, *bytesFlag, *charsFlag)
+		if *extended {
+			printExtendedHeader()
+			printExtended(counts, "-")
+		} else {
+			printCounts(counts, "", *linesFlag, *wordsFlag, *bytesFlag, *charsFlag, *longestLine, *runesFlag, *brokenFlag)
+		}
 		return
 	}
 
-	// Process each file provided as an argument.
-	var totalCounts Counts
-	for _, filename := range files {
-		// Open the file for reading.
-		file, err := os.Open(filename)
-		if err != nil {
-fmt.Fprintf(os.Stderr, "error opening file %s: %v\n", filename, err)
-			continue // Skip to the next file on error.
+	if *extended {
+		printExtendedHeader()
+	}
+
+	// Process the files concurrently, printing results as they become
+	// available in input order, and collect the totals.
+	totalCounts := countFiles(files, *jobs, *linesFlag, *wordsFlag, *bytesFlag, *charsFlag, *longestLine, *runesFlag, *brokenFlag, *extended, bytesOnly, needChars, needWords)
+
+	// If more than one file was processed, print the total counts.
+	if len(files) > 1 {
+		if *extended {
+			printExtended(totalCounts, "total")
+		} else {
+			printCounts(totalCounts, "total", *linesFlag, *wordsFlag, *bytesFlag, *charsFlag, *longestLine, *runesFlag, *brokenFlag)
 		}
+	}
+}
 
-		// The count function does the actual work.
-		counts, err := count(file)
-		file.Close() // Close the file after reading.
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error reading file %s: %v\n", filename, err)
-			continue
+// result is the outcome of counting a single file, tagged with its position
+// in the original file list so the collector can print in input order even
+// though workers finish out of order.
+type result struct {
+	index    int
+	filename string
+	counts   Counts
+	err      error
+}
+
+// countFiles counts files concurrently using a worker pool of numWorkers
+// goroutines, similar to a standard fan-out/fan-in pipeline. A single
+// collector goroutine preserves input order for printing and accumulates
+// totals, so output remains deterministic regardless of which worker
+// finishes a given file first.
+func countFiles(files []string, numWorkers int, showLines, showWords, showBytes, showChars, showMaxLineLen, showRunes, showBroken, extended, bytesOnly, needChars, needWords bool) Counts {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	jobsCh := make(chan int)
+	resultsCh := make(chan result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobsCh {
+				filename := files[idx]
+				c, err := countFile(filename, bytesOnly, needChars, needWords)
+				if err != nil {
+					resultsCh <- result{index: idx, filename: filename, err: fmt.Errorf("error reading file %s: %v", filename, err)}
+					continue
+				}
+
+				resultsCh <- result{index: idx, filename: filename, counts: c}
+			}
+		}()
+	}
+
+	go func() {
+		for i := range files {
+			jobsCh <- i
 		}
+		close(jobsCh)
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	// The collector buffers out-of-order results until the next file in
+	// input order is ready, so printing stays deterministic.
+	var totalCounts Counts
+	pending := make(map[int]result)
+	next := 0
+	for res := range resultsCh {
+		pending[res.index] = res
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			if r.err != nil {
+				fmt.Fprintln(os.Stderr, r.err)
+				continue
+			}
 
-		// Print the counts for the current file.
-		printCounts(counts, filename, *linesFlag, *wordsFlag, *bytesFlag, *charsFlag)
+			if extended {
+				printExtended(r.counts, r.filename)
+			} else {
+				printCounts(r.counts, r.filename, showLines, showWords, showBytes, showChars, showMaxLineLen, showRunes, showBroken)
+			}
 
-		// Add the counts of the current file to the total.
-		totalCounts.lines += counts.lines
-		totalCounts.words += counts.words
-		totalCounts.bytes += counts.bytes
-		totalCounts.chars += counts.chars
+			totalCounts.lines += r.counts.lines
+			totalCounts.words += r.counts.words
+			totalCounts.bytes += r.counts.bytes
+			totalCounts.chars += r.counts.chars
+			if r.counts.maxLineLen > totalCounts.maxLineLen {
+				totalCounts.maxLineLen = r.counts.maxLineLen
+			}
+			totalCounts.lfLines += r.counts.lfLines
+			totalCounts.crlfLines += r.counts.crlfLines
+			totalCounts.trailingWSLines += r.counts.trailingWSLines
+			totalCounts.nulBytes += r.counts.nulBytes
+			totalCounts.ffBytes += r.counts.ffBytes
+			totalCounts.highBytes += r.counts.highBytes
+			totalCounts.runes += r.counts.runes
+			totalCounts.broken += r.counts.broken
+		}
 	}
 
-	// If more than one file was processed, print the total counts.
-	if len(files) > 1 {
-		printCounts(totalCounts, "total", *linesFlag, *wordsFlag, *bytesFlag, *charsFlag)
+	return totalCounts
+}
+
+// readFiles0From reads a NUL-separated list of file names from path, or from
+// standard input when path is "-". Empty names are rejected, matching GNU
+// wc's handling of a malformed --files0-from list.
+func readFiles0From(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %q for reading: %v", path, err)
+	}
+
+	// A trailing NUL is common and shouldn't produce a trailing empty name.
+	data = bytes.TrimSuffix(data, []byte{0})
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	names := bytes.Split(data, []byte{0})
+	files := make([]string, 0, len(names))
+	for _, n := range names {
+		if len(n) == 0 {
+			return nil, fmt.Errorf("%s: invalid zero-length file name", path)
+		}
+		files = append(files, string(n))
 	}
+	return files, nil
+}
+
+// count reads from an io.Reader and returns the line, word, byte, and
+// character counts. needChars/needWords say whether -m chars and unicode
+// -w word boundaries were requested. Runes are decoded only for non-ASCII
+// bytes, whatever the flags, so pure-ASCII input never decodes a rune.
+func count(r io.Reader, needChars, needWords bool) (Counts, error) {
+	// bufio.NewReader chunks reads into a reusable internal buffer, so the
+	// byte-level scan below amortizes the cost of the underlying Read calls
+	// instead of doing one syscall per byte.
+	reader := bufio.NewReaderSize(r, 64*1024)
+	return scan(reader, needChars, needWords)
 }
 
-// count reads from an io.Reader and returns the line, word, byte, and character counts.
-// This function performs all counting in a single pass for efficiency.
-func count(r io.Reader) (Counts, error) {
+// scan performs the actual single-pass counting over a byteReader. It is
+// shared by count (wrapping an io.Reader) and the mmap fast path (wrapping
+// an in-memory byte slice).
+func scan(reader byteReader, needChars, needWords bool) (Counts, error) {
 	var counts Counts
-	// A bufio.Reader is used for efficient reading.
-	reader := bufio.NewReader(r)
 	inWord := false
+	lineLen := 0               // byte length of the current line, GNU wc style (tabs expand, \r is zero-width)
+	prevByte := byte(0)        // the previous byte, used to detect CRLF terminators
+	lastContentByte := byte(0) // last ASCII byte before \r/\n, used to detect trailing whitespace
 
 	for {
-		// ReadRune reads a single UTF-8 encoded Unicode character (rune) and returns
-		// the rune, its size in bytes, and an error.
-		rune, size, err := reader.ReadRune()
+		b, err := reader.ReadByte()
 		if err != nil {
-			// io.EOF signals the end of the input.
 			if err == io.EOF {
 				break
 			}
 			return Counts{}, err
 		}
 
-		// Increment byte and character counts.
-		counts.bytes += size
-		counts.chars++
+		if b < utf8.RuneSelf {
+			// ASCII fast path: lines/words/layout are all decided by plain
+			// byte comparisons, so no rune decoding is needed here at all.
+			counts.bytes++
+			if needChars {
+				counts.chars++
+			}
+			counts.runes++
+			if b == 0x00 {
+				counts.nulBytes++
+			}
+
+			switch b {
+			case '\n':
+				counts.lines++
+				if lineLen > counts.maxLineLen {
+					counts.maxLineLen = lineLen
+				}
+				lineLen = 0
+				if prevByte == '\r' {
+					counts.crlfLines++
+				} else {
+					counts.lfLines++
+				}
+				if lastContentByte == ' ' || lastContentByte == '\t' {
+					counts.trailingWSLines++
+				}
+				lastContentByte = 0
+			case '\r':
+				// Zero-width: does not advance the column or count as content.
+			case '\t':
+				lineLen += 8 - lineLen%8
+				lastContentByte = b
+			default:
+				lineLen++
+				lastContentByte = b
+			}
+			prevByte = b
+
+			isSpace := b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+			if isSpace {
+				inWord = false
+			} else if !inWord {
+				counts.words++
+				inWord = true
+			}
+			continue
+		}
+
+		// Non-ASCII: unread the lead byte and decode the full rune. This
+		// only happens for actual non-ASCII content, so a pure-ASCII file
+		// never pays for utf8.DecodeRune.
+		if err := reader.UnreadByte(); err != nil {
+			return Counts{}, err
+		}
+		peeked, _ := reader.Peek(utf8.UTFMax)
+		rn, size, err := reader.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return Counts{}, err
+		}
 
-		// Increment line count on newline characters.
-		if rune == '\n' {
-			counts.lines++
+		counts.bytes += size
+		if needChars {
+			counts.chars++
+		}
+		counts.runes++
+		if rn == utf8.RuneError && size == 1 {
+			// ReadRune reports size 1 for a byte that can't start (or
+			// continue) a valid UTF-8 sequence, as opposed to the 3-byte
+			// size it reports for a genuine U+FFFD in the input.
+			counts.broken++
 		}
+		for i := 0; i < size && i < len(peeked); i++ {
+			pb := peeked[i]
+			switch pb {
+			case 0x00:
+				counts.nulBytes++
+			case 0xFF:
+				counts.ffBytes++
+			}
+			if pb >= 0x80 {
+				counts.highBytes++
+			}
+		}
+
+		// A valid multi-byte rune is never a line terminator or tab, so it
+		// always extends the current line by one column and can't carry
+		// ASCII trailing whitespace.
+		lineLen++
+		lastContentByte = 0
+		prevByte = 0
 
-		// Word counting logic: A word is a sequence of non-whitespace characters.
-		// We are in a word if the current character is not a space.
-		// We count a new word when we transition from not being in a word to being in one.
-		if unicode.IsSpace(rune) {
+		isSpace := needWords && unicode.IsSpace(rn)
+		if isSpace {
 			inWord = false
 		} else if !inWord {
 			counts.words++
 			inWord = true
 		}
 	}
+
+	// A final, unterminated line still counts toward the longest line.
+	if lineLen > counts.maxLineLen {
+		counts.maxLineLen = lineLen
+	}
 	return counts, nil
 }
 
+// byteReader is the minimal interface scan needs; bufio.Reader and
+// sliceReader (used for the mmap fast path) both satisfy it.
+type byteReader interface {
+	ReadByte() (byte, error)
+	UnreadByte() error
+	ReadRune() (rune, int, error)
+	Peek(n int) ([]byte, error)
+}
+
+// countFile counts a single named file, taking the fastest path available:
+// a bare byte count via stat when -c is the only thing requested, an mmap
+// scan for regular files when supported, and a buffered-read scan otherwise.
+func countFile(filename string, bytesOnly, needChars, needWords bool) (Counts, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return Counts{}, err
+	}
+	defer file.Close()
+
+	fadviseSequential(file)
+
+	if bytesOnly {
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+			return Counts{bytes: int(info.Size())}, nil
+		}
+	}
+
+	if data, unmap, ok := mmapFile(file); ok {
+		defer unmap()
+		return scan(&sliceReader{data: data}, needChars, needWords)
+	}
+
+	return count(file, needChars, needWords)
+}
+
+// sliceReader adapts an in-memory byte slice (the mmap fast path) to the
+// byteReader interface without copying through an extra buffer.
+type sliceReader struct {
+	data []byte
+	pos  int
+}
+
+func (s *sliceReader) ReadByte() (byte, error) {
+	if s.pos >= len(s.data) {
+		return 0, io.EOF
+	}
+	b := s.data[s.pos]
+	s.pos++
+	return b, nil
+}
+
+func (s *sliceReader) UnreadByte() error {
+	if s.pos == 0 {
+		return errors.New("sliceReader: nothing to unread")
+	}
+	s.pos--
+	return nil
+}
+
+func (s *sliceReader) Peek(n int) ([]byte, error) {
+	end := s.pos + n
+	if end > len(s.data) {
+		end = len(s.data)
+	}
+	return s.data[s.pos:end], nil
+}
+
+func (s *sliceReader) ReadRune() (rune, int, error) {
+	if s.pos >= len(s.data) {
+		return 0, 0, io.EOF
+	}
+	rn, size := utf8.DecodeRune(s.data[s.pos:])
+	s.pos += size
+	return rn, size, nil
+}
+
 // printCounts formats and prints the counts based on the active flags.
-func printCounts(counts Counts, filename string, showLines, showWords, showBytes, showChars bool) {
+func printCounts(counts Counts, filename string, showLines, showWords, showBytes, showChars, showMaxLineLen, showRunes, showBroken bool) {
 	// Conditionally print each count field with padding for alignment.
-	// The order follows the standard `wc` output: lines, words, chars, bytes.
+	// The order follows the standard `wc` output: lines, words, chars, bytes, max-line-length.
 	if showLines {
 		fmt.Printf("%8d", counts.lines)
 	}
@@ -149,7 +490,15 @@ func printCounts(counts Counts, filename string, showLines, showWords, showBytes
 	if showBytes && showChars {
 		fmt.Printf("%8d", counts.bytes)
 	}
-
+	if showMaxLineLen {
+		fmt.Printf("%8d", counts.maxLineLen)
+	}
+	if showRunes {
+		fmt.Printf("%8d", counts.runes)
+	}
+	if showBroken {
+		fmt.Printf("%8d", counts.broken)
+	}
 
 	// Print the filename if one is provided.
 	if filename != "" {
@@ -159,3 +508,26 @@ func printCounts(counts Counts, filename string, showLines, showWords, showBytes
 		fmt.Println()
 	}
 }
+
+// printExtendedHeader prints the TSV header row for -x/--extended output.
+func printExtendedHeader() {
+	fmt.Println("file\tlines\twords\tbytes\tmax_line_len\tlf_lines\tcrlf_lines\ttrailing_ws_lines\tnul_bytes\tff_bytes\thigh_bytes")
+}
+
+// printExtended prints counts, including the extended byte stats, as a
+// single TSV row so the output stays machine-parseable.
+func printExtended(counts Counts, filename string) {
+	fmt.Printf("%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+		filename,
+		counts.lines,
+		counts.words,
+		counts.bytes,
+		counts.maxLineLen,
+		counts.lfLines,
+		counts.crlfLines,
+		counts.trailingWSLines,
+		counts.nulBytes,
+		counts.ffBytes,
+		counts.highBytes,
+	)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCountFilesPreservesOrder(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"a.txt", "b.txt", "c.txt"}
+	contents := []string{"one\n", "two\nlines\n", "three\nline\nfile\n"}
+	var paths []string
+	for i, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents[i]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+
+	// numWorkers (4) exceeds the file count, so this also exercises workers
+	// racing to finish out of order; the collector must still print in the
+	// order files were given.
+	total := countFiles(paths, 4, true, false, false, false, false, false, false, false, false, false, false)
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(names) {
+		t.Fatalf("got %d output lines, want %d: %q", len(lines), len(names), buf.String())
+	}
+	for i, name := range names {
+		if !strings.HasSuffix(lines[i], name) {
+			t.Errorf("line %d = %q, want it to end with %q", i, lines[i], name)
+		}
+	}
+
+	const wantLines = 1 + 2 + 3
+	if total.lines != wantLines {
+		t.Errorf("total lines = %d, want %d", total.lines, wantLines)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountExtendedByteStats(t *testing.T) {
+	// 0x00 is a NUL byte, 0xFF is an invalid lead byte, and a trailing 0x80
+	// is a lone (invalid) continuation byte; both the latter are >= 0x80.
+	input := "\x00\xffhello\x80"
+	counts, err := count(strings.NewReader(input), false, false)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if counts.nulBytes != 1 {
+		t.Errorf("nulBytes = %d, want 1", counts.nulBytes)
+	}
+	if counts.ffBytes != 1 {
+		t.Errorf("ffBytes = %d, want 1", counts.ffBytes)
+	}
+	if counts.highBytes != 2 {
+		t.Errorf("highBytes = %d, want 2", counts.highBytes)
+	}
+}
+
+func TestCountLineTerminatorsAndTrailingWhitespace(t *testing.T) {
+	counts, err := count(strings.NewReader("clean\ntrailing \r\ntab\t\n"), false, false)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if counts.lfLines != 2 {
+		t.Errorf("lfLines = %d, want 2", counts.lfLines)
+	}
+	if counts.crlfLines != 1 {
+		t.Errorf("crlfLines = %d, want 1", counts.crlfLines)
+	}
+	if counts.trailingWSLines != 2 {
+		t.Errorf("trailingWSLines = %d, want 2", counts.trailingWSLines)
+	}
+}
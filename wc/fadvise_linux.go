@@ -0,0 +1,26 @@
+//go:build linux && (amd64 || arm64 || ppc64 || ppc64le || s390x || mips64 || mips64le || riscv64 || loong64)
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// posixFadvSequential is POSIX_FADV_SEQUENTIAL, which has the same numeric
+// value on every Linux architecture.
+const posixFadvSequential = 2
+
+// fadviseSequential hints to the kernel that f will be read sequentially, so
+// the page cache can read ahead more aggressively. It uses a raw syscall
+// instead of golang.org/x/sys/unix, matching mmap_unix.go's use of the
+// standard library's syscall package rather than an external dependency.
+//
+// syscall.SYS_FADVISE64 only exists, with this (fd, offset, len, advice)
+// argument order, on the architectures listed in the build tag above; other
+// Linux architectures (386, arm, mips, mipsle) use the fadvise_linux_other.go
+// no-op instead, since their fadvise syscalls take different argument
+// layouts.
+func fadviseSequential(f *os.File) {
+	syscall.Syscall6(syscall.SYS_FADVISE64, f.Fd(), 0, 0, uintptr(posixFadvSequential), 0, 0)
+}
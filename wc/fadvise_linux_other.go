@@ -0,0 +1,10 @@
+//go:build linux && !(amd64 || arm64 || ppc64 || ppc64le || s390x || mips64 || mips64le || riscv64 || loong64)
+
+package main
+
+import "os"
+
+// fadviseSequential is a no-op on Linux architectures (386, arm, mips,
+// mipsle, ...) where the fadvise syscall doesn't share the (fd, offset, len,
+// advice) argument layout used in fadvise_linux.go.
+func fadviseSequential(f *os.File) {}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+import "os"
+
+// fadviseSequential is a no-op on platforms without posix_fadvise.
+func fadviseSequential(f *os.File) {}
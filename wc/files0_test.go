@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadFiles0FromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "list")
+	if err := os.WriteFile(path, []byte("a.txt\x00b.txt\x00c.txt\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readFiles0From(path)
+	if err != nil {
+		t.Fatalf("readFiles0From: %v", err)
+	}
+	want := []string{"a.txt", "b.txt", "c.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadFiles0FromRejectsEmptyName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "list")
+	if err := os.WriteFile(path, []byte("a.txt\x00\x00b.txt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readFiles0From(path); err == nil {
+		t.Fatal("expected an error for a zero-length file name, got nil")
+	}
+}
+
+func TestReadFiles0FromEmptyList(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "list")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readFiles0From(path)
+	if err != nil {
+		t.Fatalf("readFiles0From: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %v, want an empty list", got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// mmapFile is unsupported on this platform; callers fall back to normal reads.
+func mmapFile(f *os.File) (data []byte, unmap func(), ok bool) {
+	return nil, nil, false
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile memory-maps f for reading so counting a large regular file can
+// scan the page cache directly instead of copying through a read buffer.
+// ok is false when f isn't a non-empty regular file or the mapping fails,
+// in which case the caller should fall back to normal reads.
+func mmapFile(f *os.File) (data []byte, unmap func(), ok bool) {
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+		return nil, nil, false
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	return data, func() { syscall.Munmap(data) }, true
+}
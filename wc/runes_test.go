@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCountRunes(t *testing.T) {
+	const input = "héllo" // é is 2 bytes in UTF-8
+	counts, err := count(strings.NewReader(input), true, false)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if counts.bytes != len(input) {
+		t.Errorf("bytes = %d, want %d", counts.bytes, len(input))
+	}
+	wantRunes := utf8.RuneCountInString(input)
+	if counts.runes != wantRunes {
+		t.Errorf("runes = %d, want %d", counts.runes, wantRunes)
+	}
+	if counts.chars != wantRunes {
+		t.Errorf("chars = %d, want %d", counts.chars, wantRunes)
+	}
+}
+
+func TestCountBrokenUTF8(t *testing.T) {
+	// 0xC3 alone is a truncated lead byte for a 2-byte sequence.
+	const input = "ok\xc3"
+	counts, err := count(strings.NewReader(input), false, false)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if counts.broken != 1 {
+		t.Errorf("broken = %d, want 1", counts.broken)
+	}
+	if counts.runes != 3 {
+		t.Errorf("runes = %d, want 3", counts.runes)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+	"unicode"
+)
+
+// countNaive is the rune-by-rune loop count() replaced in this request. It's
+// kept only so BenchmarkCountNaive has something to compare against.
+func countNaive(r io.Reader) (Counts, error) {
+	var counts Counts
+	reader := bufio.NewReader(r)
+	inWord := false
+
+	for {
+		rn, size, err := reader.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return Counts{}, err
+		}
+
+		counts.bytes += size
+		counts.chars++
+
+		if rn == '\n' {
+			counts.lines++
+		}
+
+		if unicode.IsSpace(rn) {
+			inWord = false
+		} else if !inWord {
+			counts.words++
+			inWord = true
+		}
+	}
+	return counts, nil
+}
+
+func benchmarkInput() []byte {
+	return bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog\n"), 20000)
+}
+
+func BenchmarkCountNaive(b *testing.B) {
+	data := benchmarkInput()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := countNaive(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountFast(b *testing.B) {
+	data := benchmarkInput()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := count(bytes.NewReader(data), false, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
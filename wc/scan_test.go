@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountLongestLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"plain", "abc\nde\n", 3},
+		{"tab expands to next multiple of 8", "a\tb\n", 9},
+		{"cr is zero-width", "abc\r\n", 3},
+		{"unterminated final line counts", "short\nlongerline", 10},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			counts, err := count(strings.NewReader(tc.input), false, false)
+			if err != nil {
+				t.Fatalf("count: %v", err)
+			}
+			if counts.maxLineLen != tc.want {
+				t.Errorf("maxLineLen = %d, want %d", counts.maxLineLen, tc.want)
+			}
+		})
+	}
+}